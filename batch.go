@@ -0,0 +1,100 @@
+package keon
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/zxdev/xxhash"
+)
+
+/*
+	InsertBatch and InsertStream trade the per-call xxhash.Sum and
+	scattered memory access of Insert for a pre-hashed, bucket-sorted
+	pipeline: keys are hashed concurrently across a fan-out worker pool,
+	then sorted by target bucket so the single-threaded cuckoo shuffle
+	that follows probes hot cache lines in order instead of jumping
+	around the key slice.
+*/
+
+// InsertResult is the outcome of a single Insert/InsertHash call.
+type InsertResult struct{ Ok, Exist, NoSpace bool }
+
+// InsertBatch hashes keys across a fan-out worker pool, sorts them by
+// target bucket, then inserts the sorted stream single-threaded. Returns
+// one InsertResult per key, in the order keys were given.
+func (kn *KEON) InsertBatch(keys [][]byte) []InsertResult {
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	type hashed struct {
+		i    int
+		hash uint64
+	}
+	hashes := make([]hashed, len(keys))
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(keys) {
+		workers = len(keys)
+	}
+	chunk := (len(keys) + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for start := 0; start < len(keys); start += chunk {
+		end := start + chunk
+		if end > len(keys) {
+			end = len(keys)
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				hashes[i] = hashed{i, xxhash.Sum(keys[i])}
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	// sort by absolute bucket position so the shuffle loop below walks
+	// kn.key roughly left to right instead of at random
+	sort.Slice(hashes, func(a, b int) bool {
+		da, oa := kn.shard(hashes[a].hash)
+		db, ob := kn.shard(hashes[b].hash)
+		return oa+hashes[a].hash%da < ob+hashes[b].hash%db
+	})
+
+	results := make([]InsertResult, len(keys))
+	insert := kn.InsertHash()
+	for _, h := range hashes {
+		results[h.i] = insert(h.hash)
+	}
+
+	return results
+}
+
+// InsertStream drains ch in fixed-size windows, running each window
+// through InsertBatch's hash/sort/shuffle pipeline, and returns the
+// aggregate results in the order keys were received. Windowing keeps
+// memory bounded for a channel whose total size isn't known up front.
+func (kn *KEON) InsertStream(ch <-chan []byte) []InsertResult {
+
+	const window = 4096
+
+	var results []InsertResult
+	batch := make([][]byte, 0, window)
+
+	for key := range ch {
+		batch = append(batch, key)
+		if len(batch) == window {
+			results = append(results, kn.InsertBatch(batch)...)
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		results = append(results, kn.InsertBatch(batch)...)
+	}
+
+	return results
+}
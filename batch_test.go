@@ -0,0 +1,67 @@
+package keon_test
+
+import (
+	"testing"
+
+	"github.com/zxdev/keon"
+)
+
+func TestInsertBatch(t *testing.T) {
+
+	size := uint64(10000)
+	kn := keon.NewKEON(size)
+
+	keys := make([][]byte, size)
+	for i := range keys {
+		keys[i] = []byte{byte(i), byte(i >> 8), byte(i >> 16)}
+	}
+
+	results := kn.InsertBatch(keys)
+	if uint64(len(results)) != size {
+		t.Log("result count mismatch", len(results), size)
+		t.FailNow()
+	}
+	for i, r := range results {
+		if !r.Ok {
+			t.Log("insert failure", i, r)
+			t.FailNow()
+		}
+	}
+
+	lookup := kn.Lookup()
+	for _, key := range keys {
+		if !lookup(key) {
+			t.Log("lookup failure", key)
+			t.FailNow()
+		}
+	}
+}
+
+func TestInsertBatchEmpty(t *testing.T) {
+
+	kn := keon.NewKEON(100)
+	if results := kn.InsertBatch(nil); results != nil {
+		t.Log("expected nil results for empty input", results)
+		t.FailNow()
+	}
+}
+
+func TestInsertStream(t *testing.T) {
+
+	size := uint64(10000)
+	kn := keon.NewKEON(size)
+
+	ch := make(chan []byte)
+	go func() {
+		defer close(ch)
+		for i := uint64(0); i < size; i++ {
+			ch <- []byte{byte(i), byte(i >> 8), byte(i >> 16)}
+		}
+	}()
+
+	results := kn.InsertStream(ch)
+	if uint64(len(results)) != size {
+		t.Log("result count mismatch", len(results), size)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,187 @@
+package keon
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/zxdev/xxhash"
+)
+
+/*
+	Builder partitions incoming keys across a fixed number of shards by the
+	top bits of their hash, spills each shard's hashes to its own scratch
+	file, then runs the cuckoo shuffle for every shard concurrently on a
+	dedicated worker goroutine during Seal. This turns construction of a
+	very large key set from a single-threaded operation into an N-core one.
+	The resulting *KEON records each sub-table's depth in its file header
+	so Lookup, Insert and Remove can pick the correct sub-table by hash
+	prefix afterward.
+
+	b, _ := keon.NewKEONBuilder(count, runtime.NumCPU())
+	for ... {
+		b.Add(key)
+	}
+	kn, err := b.Seal()
+*/
+
+// Builder accumulates keys into per-shard scratch files ahead of a
+// concurrent Seal.
+type Builder struct {
+	shardBits uint64
+	dir       string
+	scratch   []*os.File
+	writer    []*bufio.Writer
+	mu        []sync.Mutex
+	count     []uint64
+	max       []uint64
+}
+
+// NewKEONBuilder creates a Builder that will shard keys across workers
+// goroutines during Seal. workers is rounded up to the next power of two
+// so a shard can be selected from the top bits of a key's hash. n is the
+// total expected key count and is distributed evenly across shards to
+// size each sub-table.
+func NewKEONBuilder(n uint64, workers int) (*Builder, error) {
+
+	if workers < 1 {
+		workers = 1
+	}
+	var shardBits uint64
+	for uint64(1)<<shardBits < uint64(workers) {
+		shardBits++
+	}
+	shards := int(uint64(1) << shardBits)
+
+	dir, err := os.MkdirTemp("", "keon-builder-*")
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Builder{
+		shardBits: shardBits,
+		dir:       dir,
+		scratch:   make([]*os.File, shards),
+		writer:    make([]*bufio.Writer, shards),
+		mu:        make([]sync.Mutex, shards),
+		count:     make([]uint64, shards),
+		max:       make([]uint64, shards),
+	}
+	for i := range b.scratch {
+		b.max[i] = n/uint64(shards) + 1
+
+		f, err := os.CreateTemp(dir, "shard-*")
+		if err != nil {
+			b.clean()
+			return nil, err
+		}
+		b.scratch[i] = f
+		b.writer[i] = bufio.NewWriter(f)
+	}
+
+	return b, nil
+}
+
+// Add a key to the builder; the key is hashed and its hash is spilled to
+// its shard's scratch file for later shuffling in Seal.
+func (b *Builder) Add(key []byte) error {
+
+	hash := xxhash.Sum(key)
+	var shard uint64
+	if b.shardBits > 0 {
+		shard = hash >> (64 - b.shardBits)
+	}
+
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], hash)
+
+	b.mu[shard].Lock()
+	defer b.mu[shard].Unlock()
+	b.count[shard]++
+	if b.count[shard] > b.max[shard] {
+		b.max[shard] = b.count[shard]
+	}
+	_, err := b.writer[shard].Write(buf[:])
+	return err
+}
+
+// Seal shuffles every shard's scratch file into its own sub-table on a
+// worker goroutine, concatenates the resulting sub-tables into a single
+// *KEON, and removes the builder's scratch directory.
+func (b *Builder) Seal() (*KEON, error) {
+
+	defer b.clean()
+
+	shards := len(b.scratch)
+	table := make([]*KEON, shards)
+	shardErr := make([]error, shards)
+
+	var wg sync.WaitGroup
+	for i := 0; i < shards; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			table[i], shardErr[i] = b.shuffle(i)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range shardErr {
+		if err != nil {
+			return nil, fmt.Errorf("keon: builder shard %d: %w", i, err)
+		}
+	}
+
+	kn := &KEON{shardBits: b.shardBits, shards: make([]uint64, shards)}
+	for i, sub := range table {
+		kn.shards[i] = sub.depth
+		kn.max += sub.max
+		kn.count += sub.count
+		kn.depth += sub.depth
+		kn.key = append(kn.key, sub.key...)
+	}
+	kn.computeShardBase()
+
+	return kn, nil
+}
+
+// shuffle reads shard i's spilled hashes back off disk and inserts them
+// single-threaded into a fresh sub-table sized for that shard alone.
+func (b *Builder) shuffle(i int) (*KEON, error) {
+
+	if err := b.writer[i].Flush(); err != nil {
+		return nil, err
+	}
+	if _, err := b.scratch[i].Seek(0, 0); err != nil {
+		return nil, err
+	}
+
+	sub := NewKEON(b.max[i])
+	insert := sub.InsertHash()
+
+	var k [8]byte
+	r := bufio.NewReader(b.scratch[i])
+	for {
+		if _, err := io.ReadFull(r, k[:]); err != nil {
+			break // io.EOF or io.UnexpectedEOF
+		}
+		if result := insert(binary.LittleEndian.Uint64(k[:])); result.NoSpace {
+			return nil, fmt.Errorf("keon: out of space")
+		}
+	}
+
+	return sub, nil
+}
+
+// clean closes the builder's scratch files and removes its temp directory.
+func (b *Builder) clean() {
+	for _, f := range b.scratch {
+		if f != nil {
+			f.Close()
+		}
+	}
+	os.RemoveAll(b.dir)
+}
@@ -0,0 +1,69 @@
+package keon_test
+
+import (
+	"testing"
+
+	"github.com/zxdev/keon"
+)
+
+func TestBuilderSeal(t *testing.T) {
+
+	size := uint64(20000)
+	b, err := keon.NewKEONBuilder(size, 4)
+	if err != nil {
+		t.Log("new builder failed", err)
+		t.FailNow()
+	}
+
+	keys := make([][]byte, size)
+	for i := range keys {
+		keys[i] = []byte{byte(i), byte(i >> 8), byte(i >> 16)}
+		if err := b.Add(keys[i]); err != nil {
+			t.Log("add failed", i, err)
+			t.FailNow()
+		}
+	}
+
+	kn, err := b.Seal()
+	if err != nil {
+		t.Log("seal failed", err)
+		t.FailNow()
+	}
+
+	if kn.Len() != size {
+		t.Log("count mismatch", kn.Len(), size)
+		t.FailNow()
+	}
+
+	lookup := kn.Lookup()
+	for _, key := range keys {
+		if !lookup(key) {
+			t.Log("lookup failure", key)
+			t.FailNow()
+		}
+	}
+
+	path := t.TempDir() + "/sharded.keon"
+	if err := kn.Write(path); err != nil {
+		t.Log("save failed", err)
+		t.FailNow()
+	}
+
+	loaded, ok := keon.Load(path)
+	if !ok {
+		t.Log("load reported invalid sharded snapshot")
+		t.FailNow()
+	}
+	if loaded.Len() != size {
+		t.Log("count mismatch after reload", loaded.Len(), size)
+		t.FailNow()
+	}
+
+	loadedLookup := loaded.Lookup()
+	for _, key := range keys {
+		if !loadedLookup(key) {
+			t.Log("lookup failure after reload", key)
+			t.FailNow()
+		}
+	}
+}
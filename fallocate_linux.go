@@ -0,0 +1,19 @@
+//go:build linux
+
+package keon
+
+import (
+	"os"
+	"syscall"
+)
+
+// fallocate claims size bytes for f up front so a sequential writer such
+// as Save fills pre-allocated pages instead of growing the file one
+// extent at a time. Best effort: errors are intentionally ignored by the
+// caller since a failure here never affects correctness.
+func fallocate(f *os.File, size int64) error {
+	if size <= 0 {
+		return nil
+	}
+	return syscall.Fallocate(int(f.Fd()), 0, 0, size)
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package keon
+
+import "os"
+
+// fallocate is a no-op on platforms without a fallocate syscall; Save
+// simply grows the file as it writes.
+func fallocate(f *os.File, size int64) error { return nil }
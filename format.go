@@ -0,0 +1,204 @@
+package keon
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+/*
+	SaveWith writes the key array in fixed-size compressed blocks instead
+	of Save's flat uncompressed layout. Cuckoo tables are sparse at the
+	tail from the density pad, so even a fully loaded table compresses
+	well; this mainly shrinks on-disk distribution artifacts, not resident
+	memory. Each block is framed as
+
+		[uncompressedLen uint32][compressedLen uint32][crc32 uint32][data]
+
+	so a corrupt block is detected without parsing the rest of the file.
+	A magic number ahead of the header lets Load recognize a SaveWith file
+	and fall back to the legacy uncompressed format transparently for
+	files written by Save.
+
+	Compression is github.com/klauspost/compress/zstd, as requested;
+	CodecNone stores blocks uncompressed for comparison.
+*/
+
+// magic identifies a SaveWith file so Load can distinguish it from the
+// legacy plain-text header, which never begins with these bytes.
+var magic = [8]byte{'K', 'E', 'O', 'N', 'z', '1', '\n', 0}
+
+// blockKeys is the default number of uint64 keys per compressed block
+// (64 KiB of keys).
+const blockKeys = 64 * 1024 / 8
+
+// Codec selects the compression used by SaveWith.
+type Codec uint8
+
+// Codec values
+const (
+	CodecZstd Codec = iota // github.com/klauspost/compress/zstd, the default
+	CodecNone              // store blocks uncompressed
+)
+
+// SaveOptions configures SaveWith.
+type SaveOptions struct {
+	Codec     Codec // compression codec, default CodecZstd
+	BlockKeys int   // uint64 keys per block, default blockKeys
+}
+
+// SaveWith writes *KEON to path in the compressed block format described
+// above using opts.
+func (kn *KEON) SaveWith(path string, opts SaveOptions) error {
+
+	if opts.BlockKeys == 0 {
+		opts.BlockKeys = blockKeys
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	buf := bufio.NewWriter(f)
+	buf.Write(magic[:])
+	fmt.Fprintln(buf, kn.validation(), kn.count, kn.max, kn.depth, kn.shardBits, uint8(opts.Codec), opts.BlockKeys)
+	if kn.shardBits > 0 {
+		for i, d := range kn.shards {
+			if i > 0 {
+				fmt.Fprint(buf, " ")
+			}
+			fmt.Fprint(buf, d)
+		}
+		fmt.Fprintln(buf)
+	}
+
+	raw := make([]byte, opts.BlockKeys*8)
+	for start := 0; start < len(kn.key); start += opts.BlockKeys {
+		end := start + opts.BlockKeys
+		if end > len(kn.key) {
+			end = len(kn.key)
+		}
+		block := raw[:(end-start)*8]
+		for i, k := range kn.key[start:end] {
+			binary.LittleEndian.PutUint64(block[i*8:], k)
+		}
+
+		compressed, err := compressBlock(opts.Codec, block)
+		if err != nil {
+			return err
+		}
+
+		var head [12]byte
+		binary.LittleEndian.PutUint32(head[0:4], uint32(len(block)))
+		binary.LittleEndian.PutUint32(head[4:8], uint32(len(compressed)))
+		binary.LittleEndian.PutUint32(head[8:12], crc32.ChecksumIEEE(compressed))
+		buf.Write(head[:])
+		buf.Write(compressed)
+	}
+
+	return buf.Flush()
+}
+
+// compressBlock compresses block with codec.
+func compressBlock(codec Codec, block []byte) ([]byte, error) {
+
+	if codec == CodecNone {
+		out := make([]byte, len(block))
+		copy(out, block)
+		return out, nil
+	}
+
+	var out bytes.Buffer
+	w, err := zstd.NewWriter(&out)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(block); err != nil {
+		w.Close()
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// decompressBlock reverses compressBlock.
+func decompressBlock(codec Codec, compressed []byte, uncompressedLen int) ([]byte, error) {
+
+	if codec == CodecNone {
+		return compressed, nil
+	}
+
+	r, err := zstd.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	out := make([]byte, uncompressedLen)
+	if _, err = io.ReadFull(r, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// loadCompressed reads a SaveWith file whose leading magic has already
+// been consumed from buf.
+func loadCompressed(buf *bufio.Reader) (*KEON, bool) {
+
+	kn := &KEON{}
+	var valid uint64
+	var codec uint8
+	var blockKeys int
+	if _, err := fmt.Fscanln(buf, &valid, &kn.count, &kn.max, &kn.depth, &kn.shardBits, &codec, &blockKeys); err != nil {
+		return nil, false
+	}
+
+	if kn.shardBits > 0 {
+		kn.shards = make([]uint64, 1<<kn.shardBits)
+		for i := range kn.shards {
+			fmt.Fscan(buf, &kn.shards[i])
+		}
+		buf.ReadString('\n')
+		kn.computeShardBase()
+	}
+	kn.key = make([]uint64, kn.depth*width)
+
+	var i int
+	for i < len(kn.key) {
+		var head [12]byte
+		if _, err := io.ReadFull(buf, head[:]); err != nil {
+			break // io.EOF or io.UnexpectedEOF
+		}
+		uncompressedLen := int(binary.LittleEndian.Uint32(head[0:4]))
+		compressedLen := int(binary.LittleEndian.Uint32(head[4:8]))
+		wantCRC := binary.LittleEndian.Uint32(head[8:12])
+
+		compressed := make([]byte, compressedLen)
+		if _, err := io.ReadFull(buf, compressed); err != nil {
+			return kn, false
+		}
+		if crc32.ChecksumIEEE(compressed) != wantCRC {
+			return kn, false // corrupt block
+		}
+
+		block, err := decompressBlock(Codec(codec), compressed, uncompressedLen)
+		if err != nil {
+			return kn, false
+		}
+		for off := 0; off < len(block); off += 8 {
+			kn.key[i] = binary.LittleEndian.Uint64(block[off:])
+			i++
+		}
+	}
+
+	return kn, valid == kn.validation()
+}
@@ -0,0 +1,97 @@
+package keon_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/zxdev/keon"
+)
+
+func testSaveWithRoundTrip(t *testing.T, codec keon.Codec) {
+
+	size := uint64(10000)
+	kn := keon.NewKEON(size)
+	insert := kn.Insert()
+
+	keys := make([][]byte, size)
+	for i := range keys {
+		keys[i] = []byte{byte(i), byte(i >> 8), byte(i >> 16)}
+		insert(keys[i])
+	}
+
+	path := t.TempDir() + "/blocks.keon"
+	if err := kn.SaveWith(path, keon.SaveOptions{Codec: codec}); err != nil {
+		t.Log("save failed", err)
+		t.FailNow()
+	}
+
+	loaded, ok := keon.Load(path)
+	if !ok {
+		t.Log("load reported invalid snapshot")
+		t.FailNow()
+	}
+
+	lookup := loaded.Lookup()
+	for _, key := range keys {
+		if !lookup(key) {
+			t.Log("lookup failure", key)
+			t.FailNow()
+		}
+	}
+}
+
+func TestSaveWithZstdRoundTrip(t *testing.T) {
+	testSaveWithRoundTrip(t, keon.CodecZstd)
+}
+
+func TestSaveWithNoneRoundTrip(t *testing.T) {
+	testSaveWithRoundTrip(t, keon.CodecNone)
+}
+
+// TestSaveWithCorruptBlock flips a byte inside the first compressed
+// block so its crc32 no longer matches, and checks Load reports the
+// snapshot as invalid rather than silently returning corrupt keys.
+func TestSaveWithCorruptBlock(t *testing.T) {
+
+	size := uint64(1000)
+	kn := keon.NewKEON(size)
+	insert := kn.Insert()
+	for i := uint64(0); i < 100; i++ {
+		insert([]byte{byte(i), byte(i >> 8)})
+	}
+
+	path := t.TempDir() + "/corrupt.keon"
+	if err := kn.SaveWith(path, keon.SaveOptions{Codec: keon.CodecNone}); err != nil {
+		t.Log("save failed", err)
+		t.FailNow()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Log("read failed", err)
+		t.FailNow()
+	}
+
+	// the first compressed block's data starts right after the 8-byte
+	// magic, the header line, and its 12-byte block frame
+	headerEnd := 8
+	for headerEnd < len(data) && data[headerEnd] != '\n' {
+		headerEnd++
+	}
+	dataStart := headerEnd + 1 + 12
+	if dataStart >= len(data) {
+		t.Log("file too small to corrupt")
+		t.FailNow()
+	}
+	data[dataStart] ^= 0xff
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Log("write failed", err)
+		t.FailNow()
+	}
+
+	if _, ok := keon.Load(path); ok {
+		t.Log("expected Load to report a corrupt block as invalid")
+		t.FailNow()
+	}
+}
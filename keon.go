@@ -2,6 +2,7 @@ package keon
 
 import (
 	"bufio"
+	"bytes"
 	"crypto/rand"
 	"encoding/binary"
 	"fmt"
@@ -53,6 +54,14 @@ type KEON struct {
 	count, max uint64   // count of items, and max items
 	depth      uint64   // depth for indexer
 	key        []uint64 // key slice
+
+	shardBits uint64   // non-zero when kn.key is partitioned into 1<<shardBits sub-tables (see Builder)
+	shards    []uint64 // depth of each sub-table, len == 1<<shardBits
+	shardBase []uint64 // precomputed key-slice offset (index units) of each sub-table
+
+	mmapped  bool     // true when kn.key aliases a read-only memory map (see LoadMmap)
+	mmapData []byte   // the raw mapped region backing kn.key, for Close to unmap
+	mmapFile *os.File // the open file behind mmapData, for Close to release
 }
 
 /*
@@ -68,14 +77,14 @@ func NewKEON(n uint64) *KEON {
 
 // Info will read and return *KEON file header information.
 func Info(path string) (result struct {
-	Checksum, Count, Max, depth uint64
-	Ok                          bool
+	Checksum, Count, Max, depth, ShardBits uint64
+	Ok                                     bool
 }) {
 
 	f, err := os.Open(path)
 	if err == nil {
 		buf := bufio.NewReader(f)
-		_, err = fmt.Fscanln(buf, &result.Checksum, &result.Count, &result.Max, &result.depth)
+		_, err = fmt.Fscanln(buf, &result.Checksum, &result.Count, &result.Max, &result.depth, &result.ShardBits)
 		f.Close()
 	}
 
@@ -97,16 +106,52 @@ func Load(path string) (*KEON, bool) {
 		return nil, false // bad file
 	}
 	defer f.Close()
-	kn.path = path
+
+	loaded, ok := LoadFrom(f)
+	if loaded == nil {
+		return nil, false
+	}
+	loaded.path = kn.path
+	return loaded, ok
+}
+
+// LoadFrom reads a *KEON's header and key data from r, the same as Load
+// but from any io.Reader rather than a file path; this is the primitive
+// Load and LoadFromStorage build on. A file written by SaveWith is
+// recognized by its leading magic number and transparently read back via
+// its compressed block format.
+func LoadFrom(r io.Reader) (*KEON, bool) {
+
+	buf := bufio.NewReader(r)
+	if peek, err := buf.Peek(len(magic)); err == nil && bytes.Equal(peek, magic[:]) {
+		buf.Discard(len(magic))
+		return loadCompressed(buf)
+	}
+
+	kn := &KEON{}
+
 	var valid uint64
-	buf := bufio.NewReader(f)
-	fmt.Fscanln(buf, &valid, &kn.count, &kn.max, &kn.depth)
+	fmt.Fscanln(buf, &valid, &kn.count, &kn.max, &kn.depth, &kn.shardBits)
+
+	if kn.shardBits > 0 {
+		// sharded tables carry their own per-shard depth on the line that
+		// follows the header, and kn.depth is already the true sum of
+		// shard depths, so skip the sizer recompute below
+		kn.shards = make([]uint64, 1<<kn.shardBits)
+		for i := range kn.shards {
+			fmt.Fscan(buf, &kn.shards[i])
+		}
+		buf.ReadString('\n')
+		kn.computeShardBase()
+		kn.key = make([]uint64, kn.depth*width)
+	} else {
+		kn.sizer(0) // kn.max configured with load
+	}
 
 	var k [8]byte
 	var i uint64
-	kn.sizer(0) // kn.max configured with load
 	for {
-		_, err = io.ReadFull(buf, k[:])
+		_, err := io.ReadFull(buf, k[:])
 		if err != nil {
 			// io.EOF or io.UnexpectedEOF
 			return kn, valid == kn.validation()
@@ -205,8 +250,32 @@ func (kn *KEON) Save() error {
 	}
 	defer f.Close()
 
-	buf := bufio.NewWriter(f)
-	fmt.Fprintln(buf, kn.validation(), kn.count, kn.max, kn.depth)
+	// best-effort: claim the key data's size up front so writing the key
+	// slice is a sequential fill rather than repeated page cache growth
+	fallocate(f, int64(len(kn.key)*8))
+
+	if err = kn.SaveTo(f); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// SaveTo writes *KEON's header and key data to w, the same as Save but to
+// any io.Writer rather than a file path; this is the primitive Save and
+// SaveToStorage build on.
+func (kn *KEON) SaveTo(w io.Writer) error {
+
+	buf := bufio.NewWriter(w)
+	fmt.Fprintln(buf, kn.validation(), kn.count, kn.max, kn.depth, kn.shardBits)
+	if kn.shardBits > 0 {
+		for i, d := range kn.shards {
+			if i > 0 {
+				fmt.Fprint(buf, " ")
+			}
+			fmt.Fprint(buf, d)
+		}
+		fmt.Fprintln(buf)
+	}
 
 	var b [8]byte
 	for i := uint64(0); i < uint64(len(kn.key)); i++ {
@@ -214,8 +283,20 @@ func (kn *KEON) Save() error {
 		buf.Write(b[:])
 	}
 
-	buf.Flush()
-	return f.Sync()
+	return buf.Flush()
+}
+
+// Close unmaps a table opened with LoadMmap and releases its backing
+// file. It is a no-op for tables not backed by a memory map.
+func (kn *KEON) Close() error {
+	if !kn.mmapped {
+		return nil
+	}
+	kn.mmapped = false
+	err := munmap(kn.mmapData)
+	kn.mmapFile.Close()
+	kn.mmapData, kn.mmapFile, kn.key = nil, nil, nil
+	return err
 }
 
 /*
@@ -238,20 +319,45 @@ func (idx *indexer) calculate(size uint64) {
 	// idx[3] holds hash of key
 }
 
+// computeShardBase precomputes the prefix-sum key-slice offset (index
+// units) of each sub-table so shard lookups are O(1) instead of summing
+// shard depths on every call.
+func (kn *KEON) computeShardBase() {
+	kn.shardBase = make([]uint64, len(kn.shards))
+	var base uint64
+	for i, d := range kn.shards {
+		kn.shardBase[i] = base
+		base += d * width
+	}
+}
+
+// shard returns the sub-table depth and key-slice offset that hash maps
+// to. A table built by Builder.Seal selects its sub-table from the top
+// shardBits bits of hash; an unsharded table always returns the whole
+// table and a zero offset.
+func (kn *KEON) shard(hash uint64) (depth, offset uint64) {
+	if kn.shardBits == 0 {
+		return kn.depth, 0
+	}
+	i := hash >> (64 - kn.shardBits)
+	return kn.shards[i], kn.shardBase[i]
+}
+
 // Lookup key in *KEON.
 func (kn *KEON) Lookup() func(key []byte) bool {
 
 	var idx indexer
-	var n, i, j uint64
+	var depth, offset, n, i, j uint64
 
 	return func(key []byte) bool {
 
 		idx[keyIndex] = xxhash.Sum(key)
-		idx.calculate(kn.depth)
+		depth, offset = kn.shard(idx[keyIndex])
+		idx.calculate(depth)
 
 		for i = 0; i < keyIndex; i++ {
 			for j = 0; j < width; j++ {
-				n = idx[i] + j
+				n = offset + idx[i] + j
 				if kn.key[n] == idx[keyIndex] {
 					return true
 				}
@@ -262,23 +368,30 @@ func (kn *KEON) Lookup() func(key []byte) bool {
 	}
 }
 
-// Remove key from *KEON.
+// Remove key from *KEON. Always returns false without modifying the
+// table when kn was opened with LoadMmap, since the underlying region is
+// a read-only memory map.
 func (kn *KEON) Remove() func(key []byte) bool {
 
 	var idx indexer
-	var n, i, j uint64
+	var depth, offset, n, i, j uint64
 
 	return func(key []byte) bool {
 
+		if kn.mmapped {
+			return false
+		}
+
 		idx[keyIndex] = xxhash.Sum((key))
-		idx.calculate(kn.depth)
+		depth, offset = kn.shard(idx[keyIndex])
+		idx.calculate(depth)
 
 		for i = 0; i < keyIndex; i++ {
 			for j = 0; j < width; j++ {
-				n = idx[i] + j
+				n = offset + idx[i] + j
 				if kn.key[n] == idx[keyIndex] {
 					copy(kn.key[n:n+width-j], kn.key[n+1:n+1+width-j]) // shift segment
-					kn.key[idx[i]+width-1] = 0                         // wipe tail
+					kn.key[offset+idx[i]+width-1] = 0                  // wipe tail
 					kn.count--
 				}
 			}
@@ -288,37 +401,41 @@ func (kn *KEON) Remove() func(key []byte) bool {
 	}
 }
 
-// Insert into *KEON.
+// InsertHash inserts a pre-computed hash directly, bypassing xxhash.Sum.
+// Builder.Seal and Merge use this to avoid re-hashing a key whose hash is
+// already known. Always fails with NoSpace when kn was opened with
+// LoadMmap, since the underlying region is a read-only memory map.
 //
 //	Ok flag on insert success
 //	Exist flag when already present (or collision)
 //	NoSpace flag with at capacity or shuffler failure
-func (kn *KEON) Insert() func(key []byte) struct{ Ok, Exist, NoSpace bool } {
+func (kn *KEON) InsertHash() func(hash uint64) struct{ Ok, Exist, NoSpace bool } {
 
 	var idx indexer
-	var n, i, j uint64
+	var depth, offset, n, i, j uint64
 	var ix, jx uint64
 	var empty bool
 
 	var node [2]uint64
 	var cyclic map[[2]uint64]uint8
 
-	return func(key []byte) (result struct{ Ok, Exist, NoSpace bool }) {
+	return func(hash uint64) (result struct{ Ok, Exist, NoSpace bool }) {
 
-		if kn.count == kn.max {
+		if kn.mmapped || kn.count == kn.max {
 			result.NoSpace = true
 			return
 		}
 
-		idx[keyIndex] = xxhash.Sum(key)
-		idx.calculate(kn.depth)
+		idx[keyIndex] = hash
+		depth, offset = kn.shard(idx[keyIndex])
+		idx.calculate(depth)
 		empty = false
 
 		// verify not already present in any target index location
 		// and record the next insertion point while checking
 		for i = 0; i < keyIndex; i++ {
 			for j = 0; j < width; j++ {
-				n = idx[i] + j
+				n = offset + idx[i] + j
 				if kn.key[n] == idx[keyIndex] {
 					result.Exist = true
 					return
@@ -332,7 +449,7 @@ func (kn *KEON) Insert() func(key []byte) struct{ Ok, Exist, NoSpace bool } {
 
 		// insert the new key at ix,jx target
 		if empty {
-			kn.key[idx[ix]+jx] = idx[keyIndex]
+			kn.key[offset+idx[ix]+jx] = idx[keyIndex]
 			kn.count++
 			result.Ok = true
 			return
@@ -357,15 +474,15 @@ func (kn *KEON) Insert() func(key []byte) struct{ Ok, Exist, NoSpace bool } {
 					// locating an open slot faster for some reason
 				}
 
-				kn.key[n], idx[keyIndex] = idx[keyIndex], kn.key[n] // swap keys to displace the key
-				idx.calculate(kn.depth)                             // generate index set for displaced key
+				kn.key[offset+n], idx[keyIndex] = idx[keyIndex], kn.key[offset+n] // swap keys to displace the key
+				idx.calculate(depth)                                              // generate index set for displaced key
 
 				for i = 0; i < keyIndex; i++ { // attempt to insert displaced key in alternate location
 					if idx[i] != ix { // avoid the common index between key and displaced key
 						for j = 0; j < width; j++ {
 							n = idx[i] + j
-							if kn.key[n] == 0 { // a new location for displaced key
-								kn.key[n] = idx[keyIndex]
+							if kn.key[offset+n] == 0 { // a new location for displaced key
+								kn.key[offset+n] = idx[keyIndex]
 								kn.count++
 								result.Ok = true
 								return
@@ -382,3 +499,17 @@ func (kn *KEON) Insert() func(key []byte) struct{ Ok, Exist, NoSpace bool } {
 		return
 	}
 }
+
+// Insert into *KEON.
+//
+//	Ok flag on insert success
+//	Exist flag when already present (or collision)
+//	NoSpace flag with at capacity or shuffler failure
+func (kn *KEON) Insert() func(key []byte) struct{ Ok, Exist, NoSpace bool } {
+
+	insert := kn.InsertHash()
+
+	return func(key []byte) struct{ Ok, Exist, NoSpace bool } {
+		return insert(xxhash.Sum(key))
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package keon
+
+// LoadMmap is unavailable on this platform; use Load instead. It always
+// returns nil, false, matching Load's (*KEON, bool) signature.
+func LoadMmap(path string) (*KEON, bool) {
+	return nil, false
+}
+
+// munmap is a no-op on this platform since LoadMmap never produces a
+// mapped region here.
+func munmap(data []byte) error { return nil }
@@ -0,0 +1,95 @@
+//go:build unix
+
+package keon
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// LoadMmap opens a *KEON by memory-mapping path instead of decoding it
+// into a freshly allocated slice, and the kn.valid validation status,
+// matching Load. kn.key aliases the mapped pages directly instead of a
+// copy, unlike Load which eagerly reads and allocates the entire key
+// array; validating the checksum still walks every mapped page once
+// before LoadMmap returns, the same cost Load pays, just without the
+// extra allocation. The returned table is read-only: Insert and
+// Remove fail without modifying it. Call Close to release the mapping.
+func LoadMmap(path string) (*KEON, bool) {
+
+	kn := &KEON{path: path}
+	kn.ext()
+
+	header, err := os.Open(kn.path)
+	if err != nil {
+		return nil, false
+	}
+
+	var valid uint64
+	buf := bufio.NewReader(header)
+	if _, err = fmt.Fscanln(buf, &valid, &kn.count, &kn.max, &kn.depth, &kn.shardBits); err != nil {
+		header.Close()
+		return nil, false
+	}
+	if kn.shardBits > 0 {
+		kn.shards = make([]uint64, 1<<kn.shardBits)
+		for i := range kn.shards {
+			fmt.Fscan(buf, &kn.shards[i])
+		}
+		buf.ReadString('\n')
+		kn.computeShardBase()
+	}
+
+	pos, err := header.Seek(0, io.SeekCurrent)
+	if err != nil {
+		header.Close()
+		return nil, false
+	}
+	headerLen := pos - int64(buf.Buffered())
+	header.Close()
+
+	f, err := os.Open(kn.path)
+	if err != nil {
+		return nil, false
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, false
+	}
+
+	// a truncated or corrupt file must fail here, before the unsafe.Slice
+	// below: an oversized kn.depth would otherwise alias past the mapped
+	// region and read adjacent process memory, or SIGBUS/SIGSEGV outright.
+	if want := headerLen + int64(kn.depth*width)*8; stat.Size() < want {
+		f.Close()
+		return nil, false
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(stat.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		f.Close()
+		return nil, false
+	}
+
+	kn.mmapped = true
+	kn.mmapData = data
+	kn.mmapFile = f
+	// the file stores keys little-endian (see Save); aliasing the mapped
+	// bytes as []uint64 is only correct on little-endian hosts
+	kn.key = unsafe.Slice((*uint64)(unsafe.Pointer(&data[headerLen])), kn.depth*width)
+
+	return kn, valid == kn.validation()
+}
+
+// munmap releases data, a region previously mapped by LoadMmap.
+func munmap(data []byte) error {
+	if data == nil {
+		return nil
+	}
+	return syscall.Munmap(data)
+}
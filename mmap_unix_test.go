@@ -0,0 +1,44 @@
+//go:build unix
+
+package keon_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/zxdev/keon"
+)
+
+func TestLoadMmapTruncated(t *testing.T) {
+
+	path := t.TempDir() + "/truncated.keon"
+
+	kn := keon.NewKEON(1000)
+	insert := kn.Insert()
+	insert([]byte("hello"))
+
+	if err := kn.Write(path); err != nil {
+		t.Log("save failed", err)
+		t.FailNow()
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		t.Log("stat failed", err)
+		t.FailNow()
+	}
+	if err := os.Truncate(path, stat.Size()/4); err != nil {
+		t.Log("truncate failed", err)
+		t.FailNow()
+	}
+
+	loaded, ok := keon.LoadMmap(path)
+	if ok {
+		t.Log("expected ok=false for a truncated file")
+		t.FailNow()
+	}
+	if loaded != nil {
+		t.Log("expected a nil *KEON for a truncated file, got one whose kn.key may alias past the mapped region")
+		t.FailNow()
+	}
+}
@@ -0,0 +1,62 @@
+package keon
+
+import "errors"
+
+/*
+	Range, HashSet and Merge let a caller enumerate or combine loaded
+	tables: Range walks every occupied slot, HashSet collects them, and
+	Merge inserts another table's hashes directly since a stored slot
+	value already is the hash - the natural counterpart to Builder, which
+	builds its sub-tables the same way.
+*/
+
+// ErrNoSpace is returned by Merge when kn runs out of room or shuffle
+// options for a hash from the table being merged in.
+var ErrNoSpace = errors.New("keon: no space for key")
+
+// Range calls fn with the hash stored in every non-zero slot, in slice
+// order, stopping early if fn returns false.
+func (kn *KEON) Range(fn func(hash uint64) bool) {
+	for _, hash := range kn.key {
+		if hash == 0 {
+			continue
+		}
+		if !fn(hash) {
+			return
+		}
+	}
+}
+
+// HashSet returns every hash stored in *KEON. Useful for a set-difference
+// between two snapshots, for rehashing into a larger table as Ratio
+// nears 100, or for exporting to another format.
+func (kn *KEON) HashSet() []uint64 {
+	set := make([]uint64, 0, kn.count)
+	kn.Range(func(hash uint64) bool {
+		set = append(set, hash)
+		return true
+	})
+	return set
+}
+
+// Merge inserts every hash in other into kn directly, skipping the
+// xxhash.Sum step since a stored slot value already is the hash. Returns
+// the number of hashes added and ErrNoSpace if kn ran out of room before
+// other was fully merged.
+func (kn *KEON) Merge(other *KEON) (added uint64, err error) {
+
+	insert := kn.InsertHash()
+	other.Range(func(hash uint64) bool {
+		result := insert(hash)
+		if result.NoSpace {
+			err = ErrNoSpace
+			return false
+		}
+		if result.Ok {
+			added++
+		}
+		return true
+	})
+
+	return
+}
@@ -0,0 +1,49 @@
+package keon_test
+
+import (
+	"testing"
+
+	"github.com/zxdev/keon"
+)
+
+func TestRangeHashSetMerge(t *testing.T) {
+
+	size := uint64(1000)
+
+	kn := keon.NewKEON(size)
+	insert := kn.Insert()
+	for i := uint64(0); i < size; i++ {
+		if !insert([]byte{byte(i), byte(i >> 8)}).Ok {
+			t.Log("insert failure", i)
+			t.FailNow()
+		}
+	}
+
+	set := kn.HashSet()
+	if uint64(len(set)) != kn.Len() {
+		t.Log("hashset length mismatch", len(set), kn.Len())
+		t.FailNow()
+	}
+
+	var ranged uint64
+	kn.Range(func(hash uint64) bool {
+		ranged++
+		return true
+	})
+	if ranged != kn.Len() {
+		t.Log("range count mismatch", ranged, kn.Len())
+		t.FailNow()
+	}
+
+	other := keon.NewKEON(size)
+	added, err := other.Merge(kn)
+	if err != nil {
+		t.Log("merge error", err)
+		t.FailNow()
+	}
+	if added != kn.Len() {
+		t.Log("merge added mismatch", added, kn.Len())
+		t.FailNow()
+	}
+	t.Log("stats", other.Len(), other.Cap(), other.Ratio())
+}
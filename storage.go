@@ -0,0 +1,68 @@
+package keon
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+/*
+	Storage is the extension point behind SaveToStorage/LoadFromStorage so
+	a *KEON snapshot can be published somewhere other than a local path.
+	DiskStorage and S3Storage (see s3_storage.go) are the two backends
+	this package ships; satisfy Storage directly to plug in anything else.
+
+	backend := keon.S3Storage{Endpoint: "https://minio.example.com",
+		Region: "us-east-1", Bucket: "snapshots", AccessKey: "...", SecretKey: "..."}
+	kn.SaveToStorage(backend, "today.keon")
+	kn, ok := keon.LoadFromStorage(backend, "today.keon")
+*/
+
+// Storage is a pluggable named-blob backend for *KEON persistence.
+type Storage interface {
+	Reader(name string) (io.ReadCloser, error)
+	Writer(name string) (io.WriteCloser, error)
+}
+
+// DiskStorage is the default Storage, reading and writing files relative
+// to Dir (the current directory when Dir is empty).
+type DiskStorage struct{ Dir string }
+
+// Reader opens name for reading under Dir.
+func (d DiskStorage) Reader(name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(d.Dir, name))
+}
+
+// Writer creates name for writing under Dir.
+func (d DiskStorage) Writer(name string) (io.WriteCloser, error) {
+	return os.Create(filepath.Join(d.Dir, name))
+}
+
+// SaveToStorage writes *KEON to name on backend. For backends such as
+// S3Storage, the actual upload and its error happen inside Writer's
+// Close, not Write, so the close error is captured here rather than
+// discarded: a Close failure (bad credentials, a network error, a
+// non-200 response) must surface as SaveToStorage's return value.
+func (kn *KEON) SaveToStorage(backend Storage, name string) (err error) {
+	w, err := backend.Writer(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := w.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	return kn.SaveTo(w)
+}
+
+// LoadFromStorage reads a *KEON named name from backend and its
+// validation status.
+func LoadFromStorage(backend Storage, name string) (*KEON, bool) {
+	r, err := backend.Reader(name)
+	if err != nil {
+		return nil, false
+	}
+	defer r.Close()
+	return LoadFrom(r)
+}
@@ -0,0 +1,64 @@
+package keon_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/zxdev/keon"
+)
+
+func TestSaveLoadStorage(t *testing.T) {
+
+	dir := t.TempDir()
+	backend := keon.DiskStorage{Dir: dir}
+
+	kn := keon.NewKEON(1000)
+	insert := kn.Insert()
+	insert([]byte("hello"))
+	insert([]byte("world"))
+
+	if err := kn.SaveToStorage(backend, "snap.keon"); err != nil {
+		t.Log("save failed", err)
+		t.FailNow()
+	}
+
+	loaded, ok := keon.LoadFromStorage(backend, "snap.keon")
+	if !ok {
+		t.Log("load reported invalid snapshot")
+		t.FailNow()
+	}
+
+	lookup := loaded.Lookup()
+	if !lookup([]byte("hello")) || !lookup([]byte("world")) {
+		t.Log("lookup failed after storage round-trip")
+		t.FailNow()
+	}
+}
+
+// failStorage is a Storage whose Writer succeeds but whose Close fails,
+// modeling a backend (like S3Storage) that only performs and validates
+// the upload when the writer is closed.
+type failStorage struct{}
+
+func (failStorage) Reader(name string) (io.ReadCloser, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (failStorage) Writer(name string) (io.WriteCloser, error) {
+	return &failWriter{}, nil
+}
+
+type failWriter struct{}
+
+func (*failWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (*failWriter) Close() error                { return errors.New("upload failed") }
+
+func TestSaveToStoragePropagatesCloseError(t *testing.T) {
+
+	kn := keon.NewKEON(1000)
+	if err := kn.SaveToStorage(failStorage{}, "snap.keon"); err == nil {
+		t.Log("expected SaveToStorage to propagate the Writer.Close error")
+		t.FailNow()
+	}
+}
@@ -0,0 +1,88 @@
+package keon
+
+import "sync"
+
+/*
+	SyncKEON wraps a *KEON with locking for a long-lived lookup service
+	that occasionally ingests updates: Lookup takes a read lock so
+	concurrent readers never block each other, Insert and Remove take the
+	exclusive lock. Snapshot returns an immutable *KEON sharing the
+	current key slice by copy-on-write so a background Save can serialize
+	a consistent view without blocking writers.
+
+	skn := keon.NewSyncKEON(keon.NewKEON(n))
+	skn.Insert(key)
+	skn.Lookup(key)
+	go skn.Snapshot().Write(path)
+*/
+
+// SyncKEON guards a *KEON for concurrent Lookup, Insert and Remove.
+type SyncKEON struct {
+	mu     sync.RWMutex
+	kn     *KEON
+	shared bool // true while kn.key also backs an outstanding Snapshot
+}
+
+// NewSyncKEON wraps kn for concurrent use.
+func NewSyncKEON(kn *KEON) *SyncKEON {
+	return &SyncKEON{kn: kn}
+}
+
+// Lookup key under a read lock. Lookup builds its own kn.Lookup() closure
+// on every call rather than sharing one on SyncKEON: that closure's
+// locals (idx, depth, offset, ...) are mutated on every probe, so two
+// goroutines running a shared closure under concurrent RLocks would
+// race on them and could return a wrong result.
+func (s *SyncKEON) Lookup(key []byte) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	lookup := s.kn.Lookup()
+	return lookup(key)
+}
+
+// Insert key under the exclusive lock.
+func (s *SyncKEON) Insert(key []byte) struct{ Ok, Exist, NoSpace bool } {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fork()
+	insert := s.kn.Insert()
+	return insert(key)
+}
+
+// Remove key under the exclusive lock.
+func (s *SyncKEON) Remove(key []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fork()
+	remove := s.kn.Remove()
+	return remove(key)
+}
+
+// fork duplicates kn.key the first time it's written to after a
+// Snapshot, so the snapshot's slice is never mutated by a write that
+// happens after it was taken. Called under the exclusive lock.
+func (s *SyncKEON) fork() {
+	if !s.shared {
+		return
+	}
+	key := make([]uint64, len(s.kn.key))
+	copy(key, s.kn.key)
+	s.kn.key = key
+	s.shared = false
+}
+
+// Snapshot returns an immutable *KEON sharing the current key slice.
+// Snapshot itself is O(1): it marks the slice shared and hands back a
+// shallow copy of kn. The slice is only actually duplicated by the next
+// Insert or Remove that follows, so a Snapshot that's never written past
+// costs nothing beyond the shallow copy. This takes the exclusive lock,
+// not a read lock: RLock allows multiple concurrent holders, and two
+// Snapshot calls racing on the shared flag would be a data race.
+func (s *SyncKEON) Snapshot() *KEON {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.shared = true
+	clone := *s.kn
+	return &clone
+}
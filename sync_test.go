@@ -0,0 +1,52 @@
+package keon_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/zxdev/keon"
+)
+
+// TestSyncKEONSnapshotConcurrent runs Snapshot concurrently with Insert
+// and Remove (and other Snapshot calls) so `go test -race` can catch a
+// data race on SyncKEON's internal shared flag.
+func TestSyncKEONSnapshotConcurrent(t *testing.T) {
+
+	size := uint64(10000)
+	skn := keon.NewSyncKEON(keon.NewKEON(size))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := []byte{byte(i), byte(i >> 8)}
+			for j := 0; j < 1000; j++ {
+				skn.Insert(key)
+				skn.Snapshot()
+				skn.Remove(key)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func BenchmarkSyncKEONLookupParallel(b *testing.B) {
+
+	size := uint64(1000000)
+	kn := keon.NewKEON(size)
+	insert := kn.Insert()
+	for i := uint64(0); i < size; i++ {
+		insert([]byte{byte(i), byte(i >> 8), byte(i >> 16), byte(i >> 24)})
+	}
+	skn := keon.NewSyncKEON(kn)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var i uint64
+		for pb.Next() {
+			skn.Lookup([]byte{byte(i), byte(i >> 8), byte(i >> 16), byte(i >> 24)})
+			i++
+		}
+	})
+}